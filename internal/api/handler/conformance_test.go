@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonathanmoreiraa/2cents/internal/domain/repository"
+	"github.com/shopspring/decimal"
+
+	"github.com/gin-gonic/gin"
+)
+
+// conformanceVector mirrors the shape of each testdata/vectors/*.json file:
+// a SimulationRequest, the mocked CDI/SELIC metric values the handler should
+// see, and the expected output of the corresponding Simulate* method.
+type conformanceVector struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Request     struct {
+		InitialValue float64  `json:"initial_value"`
+		Months       int      `json:"months"`
+		Accumulated  *float64 `json:"accumulated"`
+	} `json:"request"`
+	Metrics struct {
+		CDI   float64 `json:"cdi"`
+		SELIC float64 `json:"selic"`
+	} `json:"metrics"`
+	Expected struct {
+		Cdb      *float64              `json:"cdb"`
+		Poupanca *float64              `json:"poupanca"`
+		Series   *conformanceSeriesSet `json:"series"`
+	} `json:"expected"`
+}
+
+// conformanceSeriesSet is the per-month projection a SimulateRendimentsSeries
+// vector expects back, exercising buildCdbMonthlySeries and
+// buildPoupancaMonthlySeries end-to-end through the real HTTP handler.
+type conformanceSeriesSet struct {
+	Cdb      []conformanceSeriesPoint `json:"cdb"`
+	Poupanca []conformanceSeriesPoint `json:"poupanca"`
+}
+
+type conformanceSeriesPoint struct {
+	Month int     `json:"month"`
+	Gross float64 `json:"gross"`
+	Tax   float64 `json:"tax"`
+	Net   float64 `json:"net"`
+}
+
+// fakeMetricRepository returns fixed CDI/SELIC values instead of hitting the
+// database, so the conformance corpus is fully deterministic.
+type fakeMetricRepository struct {
+	cdi   decimal.Decimal
+	selic decimal.Decimal
+}
+
+func (f *fakeMetricRepository) GetLastMetric(ctx context.Context, metricType int) (*repository.Metric, error) {
+	switch metricType {
+	case CDI_TYPE:
+		return &repository.Metric{Value: f.cdi}, nil
+	case SELIC:
+		return &repository.Metric{Value: f.selic}, nil
+	default:
+		return &repository.Metric{Value: decimal.Zero}, nil
+	}
+}
+
+const centavoTolerance = 0.01
+
+func assertWithinCentavo(t *testing.T, vectorName, field string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > centavoTolerance {
+		t.Errorf("%s: %s = %.2f, want %.2f", vectorName, field, got, want)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	files, err := filepath.Glob("testdata/vectors/*.json")
+	if err != nil {
+		t.Fatalf("failed to list vectors: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no conformance vectors found in testdata/vectors")
+	}
+
+	for _, file := range files {
+		file := file
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+
+		var vector conformanceVector
+		if err := json.Unmarshal(raw, &vector); err != nil {
+			t.Fatalf("failed to parse %s: %v", file, err)
+		}
+
+		t.Run(vector.Name, func(t *testing.T) {
+			rh := NewRendimentsHandler(&fakeMetricRepository{
+				cdi:   decimal.NewFromFloat(vector.Metrics.CDI),
+				selic: decimal.NewFromFloat(vector.Metrics.SELIC),
+			})
+
+			var accumulated *decimal.Decimal
+			if vector.Request.Accumulated != nil {
+				a := decimal.NewFromFloat(*vector.Request.Accumulated)
+				accumulated = &a
+			}
+
+			request := SimulationRequest{
+				InitialValue: decimal.NewFromFloat(vector.Request.InitialValue),
+				Months:       vector.Request.Months,
+				Accumulated:  accumulated,
+			}
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+			if vector.Expected.Cdb != nil {
+				cdb, err := rh.SimulateCDB(ctx, request)
+				if err != nil {
+					t.Fatalf("SimulateCDB returned error: %v", err)
+				}
+				assertWithinCentavo(t, vector.Name, "cdb", cdb.InexactFloat64(), *vector.Expected.Cdb)
+			}
+
+			if vector.Expected.Poupanca != nil {
+				var poupanca decimal.Decimal
+				var err error
+				if request.Accumulated != nil {
+					poupanca, err = rh.SimulatePoupancaMonthly(ctx, request)
+				} else {
+					poupanca, err = rh.SimulatePoupanca(ctx, request)
+				}
+				if err != nil {
+					t.Fatalf("SimulatePoupanca* returned error: %v", err)
+				}
+				assertWithinCentavo(t, vector.Name, "poupanca", poupanca.InexactFloat64(), *vector.Expected.Poupanca)
+			}
+
+			if vector.Expected.Series != nil {
+				assertSeriesConformance(t, rh, vector.Name, request, vector.Expected.Series)
+			}
+		})
+	}
+}
+
+// assertSeriesConformance drives SimulateRendimentsSeries through an actual
+// gin router, so the vector also pins down the HTTP handler and the
+// buildCdbMonthlySeries/buildPoupancaMonthlySeries helpers it calls, not just
+// the underlying math.
+func assertSeriesConformance(t *testing.T, rh *RendimentsHandler, vectorName string, request SimulationRequest, want *conformanceSeriesSet) {
+	t.Helper()
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("failed to marshal series request: %v", err)
+	}
+
+	router := gin.New()
+	router.POST("/series", rh.SimulateRendimentsSeries)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/series", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("SimulateRendimentsSeries returned status %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var got SimulationSeriesResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to parse series response: %v", err)
+	}
+
+	assertSeriesPoints(t, vectorName, "cdb", got.Cdb, want.Cdb)
+	assertSeriesPoints(t, vectorName, "poupanca", got.Poupanca, want.Poupanca)
+}
+
+func assertSeriesPoints(t *testing.T, vectorName, field string, got []MonthlyDataPoint, want []conformanceSeriesPoint) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: %s series has %d entries, want %d", vectorName, field, len(got), len(want))
+	}
+
+	for i, wantPoint := range want {
+		gotPoint := got[i]
+		if gotPoint.Month != wantPoint.Month {
+			t.Errorf("%s: %s[%d].month = %d, want %d", vectorName, field, i, gotPoint.Month, wantPoint.Month)
+		}
+		assertWithinCentavo(t, vectorName, field+"[gross]", gotPoint.Gross.InexactFloat64(), wantPoint.Gross)
+		assertWithinCentavo(t, vectorName, field+"[tax]", gotPoint.Tax.InexactFloat64(), wantPoint.Tax)
+		assertWithinCentavo(t, vectorName, field+"[net]", gotPoint.Net.InexactFloat64(), wantPoint.Net)
+	}
+}
@@ -1,13 +1,14 @@
 package handler
 
 import (
-	"math"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	error_message "github.com/jonathanmoreiraa/2cents/internal/domain/error"
 	"github.com/jonathanmoreiraa/2cents/internal/domain/repository"
+	"github.com/jonathanmoreiraa/2cents/pkg/decimalmath"
 	"github.com/jonathanmoreiraa/2cents/pkg/log"
+	"github.com/shopspring/decimal"
 )
 
 type RendimentsHandler struct {
@@ -15,28 +16,48 @@ type RendimentsHandler struct {
 }
 
 type SimulationRequest struct {
-	InitialValue float64  `json:"initial_value"`
-	Months       int      `json:"months"`
-	Accumulated  *float64 `json:"accumulated"`
+	InitialValue decimal.Decimal  `json:"initial_value"`
+	Months       int              `json:"months"`
+	Accumulated  *decimal.Decimal `json:"accumulated"`
+	RealRate     *decimal.Decimal `json:"real_rate"`
 }
 
 type SimulationResponse struct {
-	CdbValue      float64 `json:"cdb"`
-	PoupancaValue float64 `json:"poupanca"`
+	CdbValue      decimal.Decimal `json:"cdb"`
+	PoupancaValue decimal.Decimal `json:"poupanca"`
+	// LciValue, LcaValue, TesouroSelicValue and TesouroIPCAValue are
+	// best-effort comparison fields: a metric lookup failing for one of
+	// them (e.g. IPCA before it's backfilled) omits that field instead of
+	// failing the whole request, since CDB/Poupanca are the values the
+	// caller actually depends on.
+	LciValue          *decimal.Decimal `json:"lci,omitempty"`
+	LcaValue          *decimal.Decimal `json:"lca,omitempty"`
+	TesouroSelicValue *decimal.Decimal `json:"tesouro_selic,omitempty"`
+	TesouroIPCAValue  *decimal.Decimal `json:"tesouro_ipca,omitempty"`
 }
 
 const (
-	CDI_TYPE              = 1
-	SELIC                 = 2
-	CDI_PERCENT           = float64(100.00)
-	BUSINESS_DAYS         = 21.0
-	UNTIL_180_DAYS        = 22.5
-	UNTIL_360_DAYS        = 20
-	UNTIL_720_DAYS        = 17.5
-	MORE_THAN_720_DAYS    = 15
-	ONE_YEAR_DAYS         = 365
-	MONTH_TAX_DEFAULT     = 0.005
-	SELIC_LESS_THAN_EIGHT = 0.085
+	CDI_TYPE      = 1
+	SELIC         = 2
+	IPCA          = 3
+	BUSINESS_DAYS = 21
+	ONE_YEAR_DAYS = 365
+)
+
+// Money-bearing constants live as decimal.Decimal values, since
+// decimal.Decimal can't be declared const.
+var (
+	cdiPercent         = decimal.NewFromInt(100)
+	hundred            = decimal.NewFromInt(100)
+	until180Days       = decimal.NewFromFloat(22.5)
+	until360Days       = decimal.NewFromInt(20)
+	until720Days       = decimal.NewFromFloat(17.5)
+	moreThan720Days    = decimal.NewFromInt(15)
+	monthTaxDefault    = decimal.NewFromFloat(0.005)
+	selicLessThanEight = decimal.NewFromFloat(0.085)
+	defaultRealRate    = decimal.NewFromFloat(0.06)
+	b3CustodyFeeAnnual = decimal.NewFromFloat(0.20)
+	one                = decimal.NewFromInt(1)
 )
 
 func NewRendimentsHandler(metricRepo repository.MetricRepository) *RendimentsHandler {
@@ -78,6 +99,30 @@ func (rh *RendimentsHandler) SimulateAllRendiments(ctx *gin.Context) {
 	simulations.CdbValue = cdb
 	simulations.PoupancaValue = poupanca
 
+	if lci, err := rh.SimulateLCI(ctx, request); err != nil {
+		log.NewLogger().Error(err)
+	} else {
+		simulations.LciValue = &lci
+	}
+
+	if lca, err := rh.SimulateLCA(ctx, request); err != nil {
+		log.NewLogger().Error(err)
+	} else {
+		simulations.LcaValue = &lca
+	}
+
+	if tesouroSelic, err := rh.SimulateTesouroSelic(ctx, request); err != nil {
+		log.NewLogger().Error(err)
+	} else {
+		simulations.TesouroSelicValue = &tesouroSelic
+	}
+
+	if tesouroIPCA, err := rh.SimulateTesouroIPCA(ctx, request); err != nil {
+		log.NewLogger().Error(err)
+	} else {
+		simulations.TesouroIPCAValue = &tesouroIPCA
+	}
+
 	ctx.JSON(http.StatusOK, simulations)
 }
 
@@ -92,18 +137,17 @@ func (rh *RendimentsHandler) SimulateMonthlyRendiments(ctx *gin.Context) {
 		return
 	}
 
-	request.InitialValue = request.InitialValue / float64(request.Months)
-	var cdbFinalValue float64
-	monthsToCalculate := request.Months
-	for i := 0; i < monthsToCalculate; i++ {
-		request.Months = i
-		cdbPerMonth, err := rh.SimulateCDB(ctx, request)
-		if err != nil {
-			continue
-		}
-		cdbFinalValue += cdbPerMonth
+	if request.Months <= 0 {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": "Invalid simulation parameters",
+		})
+		return
 	}
-	request.Months = monthsToCalculate
+
+	request.InitialValue = request.InitialValue.Div(decimal.NewFromInt(int64(request.Months)))
+	monthsToCalculate := request.Months
+	_, cdbFinalValue := rh.buildCdbMonthlySeries(ctx, request, monthsToCalculate)
 
 	poup, err := rh.SimulatePoupancaMonthly(ctx, request)
 	if err != nil {
@@ -124,101 +168,344 @@ func (rh *RendimentsHandler) SimulateMonthlyRendiments(ctx *gin.Context) {
 			})
 			return
 		}
-		cdbFinalValue += accumulatedCdb
+		cdbFinalValue = cdbFinalValue.Add(accumulatedCdb)
 	}
 
 	var simulations SimulationResponse
-	simulations.CdbValue = math.Round(cdbFinalValue*100) / 100
+	simulations.CdbValue = cdbFinalValue.Round(2)
 	simulations.PoupancaValue = poup
 
 	ctx.JSON(http.StatusOK, simulations)
 }
 
-func (rh *RendimentsHandler) SimulateCDB(ctx *gin.Context, request SimulationRequest) (float64, error) {
+// MonthlyDataPoint is one entry in a projection series: the cumulative
+// gross, tax and net values through that month, so a front-end can plot a
+// growth curve and highlight the IR bracket transitions at 180/360/720 days.
+type MonthlyDataPoint struct {
+	Month int             `json:"month"`
+	Gross decimal.Decimal `json:"gross"`
+	Tax   decimal.Decimal `json:"tax"`
+	Net   decimal.Decimal `json:"net"`
+}
+
+type SimulationSeriesResponse struct {
+	Cdb      []MonthlyDataPoint `json:"cdb"`
+	Poupanca []MonthlyDataPoint `json:"poupanca"`
+}
+
+// SimulateRendimentsSeries mirrors SimulateMonthlyRendiments but returns the
+// full month-by-month projection instead of collapsing it into a single
+// final value. The poupança series includes the Accumulated balance; the
+// CDB series covers only the monthly contributions and does not add it.
+func (rh *RendimentsHandler) SimulateRendimentsSeries(ctx *gin.Context) {
+	var request SimulationRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": "Invalid simulation parameters",
+		})
+		log.NewLogger().Error(err)
+		return
+	}
+
+	if request.Months <= 0 {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": "Invalid simulation parameters",
+		})
+		return
+	}
+
+	monthsToCalculate := request.Months
+	request.InitialValue = request.InitialValue.Div(decimal.NewFromInt(int64(monthsToCalculate)))
+
+	cdbSeries, _ := rh.buildCdbMonthlySeries(ctx, request, monthsToCalculate)
+	poupancaSeries, err := rh.buildPoupancaMonthlySeries(ctx, request, monthsToCalculate)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"code":    http.StatusInternalServerError,
+			"message": error_message.ErrSimulatePoupanca,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, SimulationSeriesResponse{
+		Cdb:      cdbSeries,
+		Poupanca: poupancaSeries,
+	})
+}
+
+// CdbBreakdown holds the gross growth, IR discount and resulting net value
+// of a single CDB simulation, so callers that need more than the final
+// number (e.g. a month-by-month series) don't have to redo the math.
+type CdbBreakdown struct {
+	Gross decimal.Decimal
+	Tax   decimal.Decimal
+	Net   decimal.Decimal
+}
+
+func (rh *RendimentsHandler) simulateCdbBreakdown(ctx *gin.Context, request SimulationRequest) (CdbBreakdown, error) {
 	metric, err := rh.metricRepository.GetLastMetric(ctx, CDI_TYPE)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"code":    http.StatusInternalServerError,
 			"message": error_message.ErrFindMetric,
 		})
-		return 0.0, nil
+		return CdbBreakdown{}, nil
 	}
 
-	dailyCdi := metric.Value.InexactFloat64() / 100.0
+	dailyCdi := metric.Value.Div(hundred)
+	adjustedDailyCdi := dailyCdi.Mul(cdiPercent).Div(hundred)
 
-	initialValue := request.InitialValue
 	months := request.Months
+	businessDays := months * BUSINESS_DAYS
+	totalDays := totalDaysFromMonths(months)
 
-	businessDays := months * int(BUSINESS_DAYS)
-	totalDays := (months / 12.0) * ONE_YEAR_DAYS
+	growth := decimalmath.Pow(one.Add(adjustedDailyCdi), businessDays)
 
-	adjustedDailyCdi := dailyCdi * (CDI_PERCENT / 100.0)
+	finalAmount := request.InitialValue.Mul(growth)
+	profit := finalAmount.Sub(request.InitialValue)
 
-	finalAmount := math.Floor(initialValue) * math.Pow(1+adjustedDailyCdi, float64(businessDays))
-	profit := finalAmount - initialValue
+	taxDiscount := profit.Mul(rh.taxRateByDays(totalDays)).Div(hundred)
+	finalAmount = finalAmount.Sub(taxDiscount)
 
-	var taxRate float64
-	switch {
-	case totalDays < 180:
-		taxRate = UNTIL_180_DAYS
-	case totalDays < 360:
-		taxRate = UNTIL_360_DAYS
-	case totalDays < 720:
-		taxRate = UNTIL_720_DAYS
-	default:
-		taxRate = MORE_THAN_720_DAYS
-	}
+	return CdbBreakdown{
+		Gross: finalAmount.Add(taxDiscount).Round(2),
+		Tax:   taxDiscount.Round(2),
+		Net:   finalAmount.Round(2),
+	}, nil
+}
 
-	taxDiscount := (profit * taxRate) / 100.0
-	finalAmount -= taxDiscount
+func (rh *RendimentsHandler) SimulateCDB(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
+	breakdown, err := rh.simulateCdbBreakdown(ctx, request)
+	if err != nil {
+		return decimal.Zero, err
+	}
 
-	return math.Round(finalAmount*100.0) / 100.0, nil
+	return breakdown.Net, nil
 }
 
-func (rh *RendimentsHandler) SimulatePoupanca(ctx *gin.Context, request SimulationRequest) (float64, error) {
+func (rh *RendimentsHandler) SimulatePoupanca(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
 	metric, err := rh.metricRepository.GetLastMetric(ctx, SELIC)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"code":    http.StatusInternalServerError,
 			"message": error_message.ErrFindMetric,
 		})
-		return 0.0, nil
+		return decimal.Zero, nil
 	}
 
-	monthTax := MONTH_TAX_DEFAULT
-	anualSelic := metric.Value.InexactFloat64() / 100
-	if anualSelic < SELIC_LESS_THAN_EIGHT {
-		monthTax = (0.7 * anualSelic) / 12
-	}
-
-	finalAmount := request.InitialValue * math.Pow(1+monthTax, float64(request.Months))
+	monthTax := rh.poupancaMonthTax(metric.Value)
+	growth := decimalmath.Pow(one.Add(monthTax), request.Months)
+	finalAmount := request.InitialValue.Mul(growth)
 
-	return math.Round(finalAmount*100.0) / 100.0, nil
+	return finalAmount.Round(2), nil
 }
 
-func (rh *RendimentsHandler) SimulatePoupancaMonthly(ctx *gin.Context, request SimulationRequest) (float64, error) {
+func (rh *RendimentsHandler) SimulatePoupancaMonthly(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
 	metric, err := rh.metricRepository.GetLastMetric(ctx, SELIC)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"code":    http.StatusInternalServerError,
 			"message": error_message.ErrFindMetric,
 		})
-		return 0.0, nil
+		return decimal.Zero, nil
 	}
 
-	accumulatedValue := 0.0
+	accumulatedValue := decimal.Zero
 	if request.Accumulated != nil {
 		accumulatedValue = *request.Accumulated
 	}
 
-	monthTax := MONTH_TAX_DEFAULT
-	anualSelic := metric.Value.InexactFloat64() / 100
-	if anualSelic < SELIC_LESS_THAN_EIGHT {
-		monthTax = (0.7 * anualSelic) / 12
+	monthTax := rh.poupancaMonthTax(metric.Value)
+	growth := decimalmath.Pow(one.Add(monthTax), request.Months)
+
+	// ((1+r)^n - 1) / r is the future value of a monthly contribution, but
+	// it's undefined at r=0. Its limit as r->0 is n, so a zero month tax
+	// means the contributions just add up linearly: InitialValue*Months.
+	futureAmount := request.InitialValue.Mul(decimal.NewFromInt(int64(request.Months)))
+	if !monthTax.IsZero() {
+		futureAmount = request.InitialValue.Mul(growth.Sub(one)).Div(monthTax)
+	}
+	totalAmount := accumulatedValue.Mul(growth).Add(futureAmount)
+
+	return totalAmount.Round(2), nil
+}
+
+func (rh *RendimentsHandler) poupancaMonthTax(selicMetric decimal.Decimal) decimal.Decimal {
+	anualSelic := selicMetric.Div(hundred)
+	if anualSelic.LessThan(selicLessThanEight) {
+		return decimal.NewFromFloat(0.7).Mul(anualSelic).Div(decimal.NewFromInt(12))
+	}
+
+	return monthTaxDefault
+}
+
+// SimulateLCI grows the initial value at 100% of the CDI, like SimulateCDB,
+// but LCI is IR-exempt for individuals, so the 15/17.5/20/22.5 tax table is
+// never applied.
+func (rh *RendimentsHandler) SimulateLCI(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
+	return rh.simulateTaxExemptCdi(ctx, request)
+}
+
+// SimulateLCA mirrors SimulateLCI: LCA shares the same CDI-indexed,
+// IR-exempt rules for individuals.
+func (rh *RendimentsHandler) SimulateLCA(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
+	return rh.simulateTaxExemptCdi(ctx, request)
+}
+
+// simulateTaxExemptCdi is only ever called as a best-effort comparison
+// field (see SimulateAllRendiments), so unlike the CDB/Poupanca paths it
+// returns the metric lookup error as-is instead of aborting ctx, letting
+// the caller decide whether a missing metric is fatal.
+func (rh *RendimentsHandler) simulateTaxExemptCdi(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
+	metric, err := rh.metricRepository.GetLastMetric(ctx, CDI_TYPE)
+	if err != nil {
+		return decimal.Zero, err
 	}
 
-	futureAmount := request.InitialValue * (math.Pow(1+monthTax, float64(request.Months)) - 1) / monthTax
-	totalAmount := accumulatedValue*math.Pow(1+monthTax, float64(request.Months)) + futureAmount
+	dailyCdi := metric.Value.Div(hundred)
+	adjustedDailyCdi := dailyCdi.Mul(cdiPercent).Div(hundred)
+
+	businessDays := request.Months * BUSINESS_DAYS
+	growth := decimalmath.Pow(one.Add(adjustedDailyCdi), businessDays)
+
+	return request.InitialValue.Mul(growth).Round(2), nil
+}
+
+// SimulateTesouroSelic accrues the initial value at the SELIC rate
+// compounded over the holding period (as a fraction of a year, months/12),
+// discounts the regressive IR table by holding period and charges the
+// 0.20% a.a. B3 custody fee, pro-rated by the same fraction.
+//
+// Like simulateTaxExemptCdi, this is only ever used as a best-effort
+// comparison field, so a metric lookup failure is returned as-is rather
+// than aborting ctx.
+func (rh *RendimentsHandler) SimulateTesouroSelic(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
+	metric, err := rh.metricRepository.GetLastMetric(ctx, SELIC)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	anualSelic := metric.Value.Div(hundred)
+	return rh.simulateGovernmentBond(request, anualSelic), nil
+}
+
+// SimulateTesouroIPCA compounds the IPCA index with the contracted real
+// rate, (1+IPCA)*(1+realRate)-1, then applies the same custody fee and
+// holding-period IR table used by Tesouro Selic. IPCA is a newer metric
+// type that may have no rows yet, so (like Tesouro Selic) a lookup failure
+// is returned as-is for the caller to treat as best-effort.
+func (rh *RendimentsHandler) SimulateTesouroIPCA(ctx *gin.Context, request SimulationRequest) (decimal.Decimal, error) {
+	metric, err := rh.metricRepository.GetLastMetric(ctx, IPCA)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	anualIpca := metric.Value.Div(hundred)
+	realRate := defaultRealRate
+	if request.RealRate != nil {
+		realRate = *request.RealRate
+	}
+
+	anualRate := one.Add(anualIpca).Mul(one.Add(realRate)).Sub(one)
+	return rh.simulateGovernmentBond(request, anualRate), nil
+}
+
+// simulateGovernmentBond holds the math shared by Tesouro Selic and
+// Tesouro IPCA+: compound initialValue over months/12 of a year at
+// anualRate, subtract the pro-rated B3 custody fee, then the
+// holding-period IR discount on the resulting profit.
+func (rh *RendimentsHandler) simulateGovernmentBond(request SimulationRequest, anualRate decimal.Decimal) decimal.Decimal {
+	months := request.Months
+	totalDays := totalDaysFromMonths(months)
+
+	growth := decimalmath.PowFrac(one.Add(anualRate), months, 12)
+	finalAmount := request.InitialValue.Mul(growth)
+	finalAmount = finalAmount.Sub(rh.custodyFee(request.InitialValue, months))
+
+	profit := finalAmount.Sub(request.InitialValue)
+	finalAmount = finalAmount.Sub(profit.Mul(rh.taxRateByDays(totalDays)).Div(hundred))
+
+	return finalAmount.Round(2)
+}
+
+func (rh *RendimentsHandler) taxRateByDays(totalDays float64) decimal.Decimal {
+	switch {
+	case totalDays < 180:
+		return until180Days
+	case totalDays < 360:
+		return until360Days
+	case totalDays < 720:
+		return until720Days
+	default:
+		return moreThan720Days
+	}
+}
+
+func (rh *RendimentsHandler) custodyFee(initialValue decimal.Decimal, months int) decimal.Decimal {
+	yearFraction := decimal.NewFromInt(int64(months)).Div(decimal.NewFromInt(12))
+	return initialValue.Mul(b3CustodyFeeAnnual).Div(hundred).Mul(yearFraction)
+}
+
+func totalDaysFromMonths(months int) float64 {
+	return (float64(months) / 12.0) * float64(ONE_YEAR_DAYS)
+}
+
+// buildCdbMonthlySeries reproduces the monthly-deposit loop that used to
+// live inline in SimulateMonthlyRendiments: each iteration simulates the
+// CDB contribution made in month i, and the series accumulates those
+// contributions into the running total through that month. It also returns
+// the final cumulative net value, so callers that only need the total (the
+// pre-existing endpoints) don't have to sum the series themselves.
+func (rh *RendimentsHandler) buildCdbMonthlySeries(ctx *gin.Context, request SimulationRequest, monthsToCalculate int) ([]MonthlyDataPoint, decimal.Decimal) {
+	series := make([]MonthlyDataPoint, 0, monthsToCalculate)
+	cumulativeGross, cumulativeTax, cumulativeNet := decimal.Zero, decimal.Zero, decimal.Zero
+
+	contribution := request
+	for i := 0; i < monthsToCalculate; i++ {
+		contribution.Months = i
+		breakdown, err := rh.simulateCdbBreakdown(ctx, contribution)
+		if err != nil {
+			continue
+		}
+
+		cumulativeGross = cumulativeGross.Add(breakdown.Gross)
+		cumulativeTax = cumulativeTax.Add(breakdown.Tax)
+		cumulativeNet = cumulativeNet.Add(breakdown.Net)
+
+		series = append(series, MonthlyDataPoint{
+			Month: i + 1,
+			Gross: cumulativeGross.Round(2),
+			Tax:   cumulativeTax.Round(2),
+			Net:   cumulativeNet.Round(2),
+		})
+	}
+
+	return series, cumulativeNet
+}
+
+// buildPoupancaMonthlySeries emits the accumulated poupança total through
+// each month, reusing SimulatePoupancaMonthly at increasing holding periods.
+// Poupança is IR-exempt, so gross and net are always equal.
+func (rh *RendimentsHandler) buildPoupancaMonthlySeries(ctx *gin.Context, request SimulationRequest, monthsToCalculate int) ([]MonthlyDataPoint, error) {
+	series := make([]MonthlyDataPoint, 0, monthsToCalculate)
+
+	monthly := request
+	for i := 1; i <= monthsToCalculate; i++ {
+		monthly.Months = i
+		net, err := rh.SimulatePoupancaMonthly(ctx, monthly)
+		if err != nil {
+			return nil, err
+		}
+
+		series = append(series, MonthlyDataPoint{
+			Month: i,
+			Gross: net,
+			Tax:   decimal.Zero,
+			Net:   net,
+		})
+	}
 
-	return math.Round(totalAmount*100.0) / 100.0, nil
+	return series, nil
 }
@@ -3,6 +3,7 @@ package handler
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -293,15 +294,20 @@ func (cr *SavingHandler) Delete(ctx *gin.Context) {
 		return
 	}
 
-	if len(allSavings) > 0 {
-		for _, saving := range allSavings {
-			if saving.Priority > deletedPriority {
-				saving.Priority = saving.Priority - 1
-				err = cr.savingUseCase.Update(ctx.Request.Context(), savingToDelete)
-				if err != nil {
-					log.NewLogger().Error("Erro ao atualizar prioridade do saving:", err)
-				}
-			}
+	var compaction []saving_contract.PriorityUpdate
+	for _, saving := range allSavings {
+		if saving.Priority > deletedPriority {
+			compaction = append(compaction, saving_contract.PriorityUpdate{
+				ID:       saving.ID,
+				Priority: saving.Priority - 1,
+			})
+		}
+	}
+
+	if len(compaction) > 0 {
+		err = cr.savingUseCase.ReorderPriorities(ctx.Request.Context(), userId, compaction)
+		if err != nil {
+			log.NewLogger().Error("Erro ao compactar prioridades após deletar saving:", err)
 		}
 	}
 
@@ -310,3 +316,128 @@ func (cr *SavingHandler) Delete(ctx *gin.Context) {
 		"message": "Caixinha deletada com sucesso!",
 	})
 }
+
+// SavingReorderRequest is the body of PATCH /savings/reorder: the full
+// desired priority order for the authenticated user's caixinhas.
+type SavingReorderRequest []saving_contract.PriorityUpdate
+
+func (cr *SavingHandler) Reorder(ctx *gin.Context) {
+	var reorderRequest SavingReorderRequest
+	if err := ctx.ShouldBindJSON(&reorderRequest); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": error_message.ErrReorderSaving,
+		})
+		log.NewLogger().Error(err)
+		return
+	}
+
+	userId, err := GetUserIdByToken(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":      http.StatusUnprocessableEntity,
+			"message":   error_message.ErrReorderSaving,
+			"more_info": "Verifique as informações do usuário logado!",
+		})
+		return
+	}
+
+	if !isContiguousWithoutDuplicates(reorderRequest) || hasDuplicateIDs(reorderRequest) {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": error_message.ErrReorderSavingInvalidPriorities,
+		})
+		return
+	}
+
+	allSavings, err := cr.savingUseCase.GetAllSavings(ctx.Request.Context(), userId)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": error_message.ErrReorderSaving,
+		})
+		return
+	}
+
+	ownedByUser := make(map[int]bool, len(allSavings))
+	for _, saving := range allSavings {
+		ownedByUser[saving.ID] = true
+	}
+
+	for _, update := range reorderRequest {
+		if !ownedByUser[update.ID] {
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+				"code":    http.StatusUnprocessableEntity,
+				"message": "Erro ao reordenar caixinha com esse usuário",
+			})
+			log.NewLogger().Error(
+				fmt.Errorf("A caixinha com id %d não está relacionada com o usuário logado com id %d", update.ID, userId),
+			)
+			return
+		}
+	}
+
+	if len(reorderRequest) != len(allSavings) {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": error_message.ErrReorderSavingInvalidPriorities,
+		})
+		log.NewLogger().Error(
+			fmt.Errorf("reordenação parcial: usuário %d tem %d caixinhas mas enviou %d prioridades", userId, len(allSavings), len(reorderRequest)),
+		)
+		return
+	}
+
+	err = cr.savingUseCase.ReorderPriorities(ctx.Request.Context(), userId, reorderRequest)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"code":    http.StatusUnprocessableEntity,
+			"message": error_message.ErrReorderSaving,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "Caixinhas reordenadas com sucesso!",
+	})
+}
+
+// isContiguousWithoutDuplicates checks the requested priorities form the
+// set {1, ..., len(updates)} with no repeats, so a reorder can never leave
+// gaps or two caixinhas tied on the same priority.
+func isContiguousWithoutDuplicates(updates []saving_contract.PriorityUpdate) bool {
+	if len(updates) == 0 {
+		return false
+	}
+
+	priorities := make([]int, len(updates))
+	for i, update := range updates {
+		priorities[i] = update.Priority
+	}
+
+	sort.Ints(priorities)
+	for i, priority := range priorities {
+		if priority != i+1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasDuplicateIDs reports whether the same caixinha ID appears more than
+// once in the reorder payload. A contiguous, duplicate-free priority set
+// isn't enough on its own: {A:1, A:2, B:3} is contiguous over {1,2,3} even
+// though A is updated twice and some other owned caixinha is never touched.
+func hasDuplicateIDs(updates []saving_contract.PriorityUpdate) bool {
+	seen := make(map[int]bool, len(updates))
+	for _, update := range updates {
+		if seen[update.ID] {
+			return true
+		}
+		seen[update.ID] = true
+	}
+
+	return false
+}
@@ -0,0 +1,23 @@
+// Package error centralizes the user-facing error messages returned by the
+// API handlers, so every endpoint reports the same wording for the same
+// failure instead of each handler inventing its own string.
+package error
+
+const (
+	ErrCreateSaving                   = "Erro ao criar caixinha"
+	ErrCreateDuplicateEmergencyFund   = "Já existe uma reserva de emergência cadastrada"
+	ErrCreateExpenseFromSaving        = "Erro ao criar despesa a partir da caixinha"
+	ErrUpdateSaving                   = "Erro ao atualizar caixinha"
+	ErrDeleteSaving                   = "Erro ao deletar caixinha"
+	ErrFindSaving                     = "Caixinha não encontrada"
+	ErrReorderSaving                  = "Erro ao reordenar caixinhas"
+	ErrReorderSavingInvalidPriorities = "As prioridades informadas são inválidas"
+
+	ErrFindMetric           = "Erro ao buscar indicador"
+	ErrSimulateCdb          = "Erro ao simular CDB"
+	ErrSimulatePoupanca     = "Erro ao simular poupança"
+	ErrSimulateLci          = "Erro ao simular LCI"
+	ErrSimulateLca          = "Erro ao simular LCA"
+	ErrSimulateTesouroSelic = "Erro ao simular Tesouro Selic"
+	ErrSimulateTesouroIpca  = "Erro ao simular Tesouro IPCA+"
+)
@@ -0,0 +1,30 @@
+// Package contract defines the boundary between the saving handler and its
+// use case implementation, so the handler depends only on behavior, not on
+// how savings are persisted.
+package contract
+
+import (
+	"context"
+
+	"github.com/jonathanmoreiraa/2cents/internal/domain/model"
+)
+
+// PriorityUpdate pairs a caixinha ID with the priority it should be moved
+// to, the unit of work for both a full reorder and a post-delete
+// compaction.
+type PriorityUpdate struct {
+	ID       int `json:"id"`
+	Priority int `json:"priority"`
+}
+
+type SavingUseCase interface {
+	Create(ctx context.Context, saving model.Saving) (model.Saving, error)
+	GetAllSavings(ctx context.Context, userId int) ([]model.Saving, error)
+	GetSaving(ctx context.Context, id int) (model.Saving, error)
+	Update(ctx context.Context, saving model.Saving) error
+	Delete(ctx context.Context, saving model.Saving) error
+	// ReorderPriorities applies every update in a single transaction, so a
+	// reorder or post-delete compaction never leaves priorities
+	// half-migrated if one update fails partway through.
+	ReorderPriorities(ctx context.Context, userId int, updates []PriorityUpdate) error
+}
@@ -0,0 +1,69 @@
+// Package decimalmath provides the exponentiation helpers decimal.Decimal
+// itself doesn't: money math needs deterministic, JSON-round-trippable
+// results, which math.Pow can't guarantee once the operands come from
+// user input instead of from a fixed set of constants.
+package decimalmath
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+const nthRootMaxIterations = 100
+
+var nthRootTolerance = decimal.New(1, -18)
+
+// Pow raises base to a non-negative or negative integer exponent using
+// repeated multiplication, so a non-negative exponent gives exact decimal
+// arithmetic with no binary-float drift. A negative exponent divides 1 by
+// the positive power, which rounds to decimal.DivisionPrecision digits.
+func Pow(base decimal.Decimal, exp int) decimal.Decimal {
+	if exp < 0 {
+		return decimal.NewFromInt(1).Div(Pow(base, -exp))
+	}
+
+	result := decimal.NewFromInt(1)
+	for i := 0; i < exp; i++ {
+		result = result.Mul(base)
+	}
+	return result
+}
+
+// PowFrac raises base to the fractional exponent numerator/denominator
+// (e.g. months/12 for a partial-year holding period) as Pow(base,
+// numerator) followed by an integer denominator-th root, computed with
+// Newton's method so the whole calculation stays in decimal.Decimal.
+func PowFrac(base decimal.Decimal, numerator, denominator int) decimal.Decimal {
+	if denominator == 1 {
+		return Pow(base, numerator)
+	}
+
+	return nthRoot(Pow(base, numerator), denominator)
+}
+
+// nthRoot solves y^n = x for y via Newton-Raphson, seeded from the
+// float64 approximation and refined in decimal.Decimal until the update
+// step is smaller than nthRootTolerance or nthRootMaxIterations steps have
+// run, in which case the last guess is returned.
+func nthRoot(x decimal.Decimal, n int) decimal.Decimal {
+	if x.IsZero() || n == 1 {
+		return x
+	}
+
+	seed := math.Pow(x.InexactFloat64(), 1.0/float64(n))
+	guess := decimal.NewFromFloat(seed)
+
+	nDec := decimal.NewFromInt(int64(n))
+	nMinusOne := decimal.NewFromInt(int64(n - 1))
+
+	for i := 0; i < nthRootMaxIterations; i++ {
+		next := nMinusOne.Mul(guess).Add(x.Div(Pow(guess, n-1))).Div(nDec)
+		if next.Sub(guess).Abs().LessThan(nthRootTolerance) {
+			return next
+		}
+		guess = next
+	}
+
+	return guess
+}
@@ -0,0 +1,32 @@
+package decimalmath
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPow(t *testing.T) {
+	got := Pow(decimal.NewFromFloat(1.1), 3)
+	want := decimal.NewFromFloat(1.331)
+	if !got.Sub(want).Abs().LessThan(decimal.New(1, -9)) {
+		t.Errorf("Pow(1.1, 3) = %s, want %s", got, want)
+	}
+}
+
+func TestPowFracHalfYear(t *testing.T) {
+	// (1.21)^(6/12) should land back on 1.1, since 1.1^2 = 1.21.
+	got := PowFrac(decimal.NewFromFloat(1.21), 6, 12)
+	want := decimal.NewFromFloat(1.1)
+	if !got.Sub(want).Abs().LessThan(decimal.New(1, -9)) {
+		t.Errorf("PowFrac(1.21, 6, 12) = %s, want %s", got, want)
+	}
+}
+
+func TestPowFracWholeYear(t *testing.T) {
+	got := PowFrac(decimal.NewFromFloat(1.1), 12, 12)
+	want := decimal.NewFromFloat(1.1)
+	if !got.Sub(want).Abs().LessThan(decimal.New(1, -9)) {
+		t.Errorf("PowFrac(1.1, 12, 12) = %s, want %s", got, want)
+	}
+}